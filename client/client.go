@@ -0,0 +1,28 @@
+package client
+
+import "github.com/glair-ai/glair-vision-go"
+
+// Client aggregates the Vision API service subsystems - FaceBio, OCR,
+// and Jobs - each sharing the same configuration
+type Client struct {
+	Config  *glair.Config
+	FaceBio *FaceBio
+	OCR     *OCR
+	Jobs    *Jobs
+}
+
+// New creates a Client for config, wiring every service subsystem to
+// share it. FaceBio and OCR submit their ...Async operations through
+// the same Jobs instance exposed on Client.Jobs, so a handle returned
+// by e.g. FaceBio.PassiveLivenessAsync can be waited on via
+// Client.Jobs.Wait
+func New(config *glair.Config) *Client {
+	jobs := NewJobs(config, nil)
+
+	return &Client{
+		Config:  config,
+		FaceBio: &FaceBio{config: config, jobs: jobs},
+		OCR:     &OCR{config: config, jobs: jobs},
+		Jobs:    jobs,
+	}
+}
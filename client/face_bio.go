@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+
+	"github.com/glair-ai/glair-vision-go"
+)
+
+// faceBioService is the service path used to build FaceBio endpoint
+// URLs
+const faceBioService = "face-biometric"
+
+// FaceBio provides face biometric operations such as passive liveness
+// detection
+type FaceBio struct {
+	config *glair.Config
+	jobs   *Jobs
+}
+
+// PassiveLiveness performs synchronous passive liveness detection on
+// input.Image
+func (f *FaceBio) PassiveLiveness(ctx context.Context, input glair.PassiveLivenessInput) (*glair.PassiveLivenessResult, error) {
+	req, err := newMultipartRequest(ctx, f.config, faceBioService, "passive-liveness", input.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f.config.Transport().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result glair.PassiveLivenessResult
+	if err := decodeJSON(res, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// PassiveLivenessAsync submits input as an asynchronous job instead of
+// waiting synchronously, for large frames where processing may take
+// too long for a single request/response cycle. Use f.jobs.Wait (or
+// Client.Jobs.Wait) with the returned JobHandle.ID to retrieve the
+// result once it is ready
+func (f *FaceBio) PassiveLivenessAsync(ctx context.Context, input glair.PassiveLivenessInput) (JobHandle, error) {
+	body, contentType, err := newMultipartBody(input.Image)
+	if err != nil {
+		return JobHandle{}, err
+	}
+
+	return f.jobs.Submit(ctx, JobRequest{
+		Endpoint:    "passive-liveness",
+		Payload:     body,
+		ContentType: contentType,
+	})
+}
@@ -0,0 +1,313 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/glair-ai/glair-vision-go"
+)
+
+// jobsService is the service name used to build job endpoint URLs,
+// analogous to Harbor's scan job service
+const jobsService = "jobs"
+
+// JobStatus represents the lifecycle state of an asynchronous job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// terminal reports whether status will no longer change
+func (s JobStatus) terminal() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed
+}
+
+// Webhook registers a callback URL that GLAIR Vision API will call
+// once a job reaches a terminal state, for users who prefer push over
+// poll
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// JobRequest describes a long-running Vision operation to submit, such
+// as large document OCR or passive liveness on a large frame
+type JobRequest struct {
+	// Endpoint is the Vision API endpoint that performs the operation,
+	// e.g. "general-document" or "passive-liveness"
+	Endpoint string
+	// Payload is the request body to submit, typically a multipart
+	// image upload
+	Payload io.Reader
+	// ContentType is the content type of Payload
+	ContentType string
+	// Webhook, when set, registers a callback to be called instead of
+	// requiring the caller to poll Status/Wait
+	Webhook *Webhook
+}
+
+// JobHandle identifies a submitted job. It carries no result and no
+// decoder of its own: pass its ID to Jobs.Wait and use the returned
+// JobResult's Decode once the job has succeeded
+type JobHandle struct {
+	ID          string    `json:"id"`
+	Endpoint    string    `json:"endpoint"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// JobResult carries the raw response body of a succeeded job. Use
+// Decode to unmarshal it into a typed result, e.g. ocr.GeneralDocument
+type JobResult struct {
+	Raw json.RawMessage
+}
+
+// Decode unmarshals the job's raw result into v
+func (r JobResult) Decode(v interface{}) error {
+	return json.Unmarshal(r.Raw, v)
+}
+
+// JobRecord is the metadata JobStore implementations persist for a
+// submitted job so that Wait can resume it across a process restart
+type JobRecord struct {
+	ID         string    `json:"id"`
+	Endpoint   string    `json:"endpoint"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastStatus JobStatus `json:"last_status"`
+}
+
+// JobStore persists JobRecords. The in-memory implementation used by
+// default loses track of jobs on process restart; users who need to
+// resume Wait across restarts should supply a disk- or DB-backed
+// implementation
+type JobStore interface {
+	Save(ctx context.Context, record JobRecord) error
+	Load(ctx context.Context, id string) (JobRecord, error)
+}
+
+// inMemoryJobStore is the default JobStore, backed by a map
+type inMemoryJobStore struct {
+	mu      sync.Mutex
+	records map[string]JobRecord
+}
+
+func newInMemoryJobStore() *inMemoryJobStore {
+	return &inMemoryJobStore{records: map[string]JobRecord{}}
+}
+
+func (s *inMemoryJobStore) Save(ctx context.Context, record JobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.ID] = record
+	return nil
+}
+
+func (s *inMemoryJobStore) Load(ctx context.Context, id string) (JobRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return JobRecord{}, fmt.Errorf("client: no job record found for id %q", id)
+	}
+
+	return record, nil
+}
+
+// WaitOptions configures how Wait polls for a job's terminal state
+type WaitOptions struct {
+	// PollInterval is the initial delay between status checks.
+	// Defaults to 2 seconds
+	PollInterval time.Duration
+	// MaxInterval caps the backoff applied to PollInterval between
+	// checks. Defaults to 30 seconds
+	MaxInterval time.Duration
+	// Timeout bounds how long Wait polls before giving up. Zero means
+	// no timeout beyond ctx's own deadline
+	Timeout time.Duration
+}
+
+// Jobs provides submission and polling of asynchronous Vision
+// operations, such as large document OCR or passive liveness on large
+// frames, that are modeled as jobs rather than synchronous requests
+type Jobs struct {
+	config *glair.Config
+	store  JobStore
+}
+
+// NewJobs creates a Jobs subsystem using config for authentication and
+// transport. A nil store defaults to an in-memory JobStore
+func NewJobs(config *glair.Config, store JobStore) *Jobs {
+	if store == nil {
+		store = newInMemoryJobStore()
+	}
+
+	return &Jobs{config: config, store: store}
+}
+
+// Submit submits req as an asynchronous job and returns a JobHandle
+// that Status and Wait can use to track it
+func (j *Jobs) Submit(ctx context.Context, req JobRequest) (JobHandle, error) {
+	url := j.config.GetEndpointURL(jobsService, req.Endpoint)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, req.Payload)
+	if err != nil {
+		return JobHandle{}, err
+	}
+	if req.ContentType != "" {
+		httpReq.Header.Set("Content-Type", req.ContentType)
+	}
+	if req.Webhook != nil {
+		body, err := json.Marshal(req.Webhook)
+		if err != nil {
+			return JobHandle{}, err
+		}
+		httpReq.Header.Set("X-Glair-Webhook", string(body))
+	}
+
+	if err := j.config.Authorize(ctx, httpReq); err != nil {
+		return JobHandle{}, err
+	}
+
+	res, err := j.config.Transport().Do(httpReq)
+	if err != nil {
+		return JobHandle{}, err
+	}
+	defer res.Body.Close()
+
+	var handle JobHandle
+	if err := json.NewDecoder(res.Body).Decode(&handle); err != nil {
+		return JobHandle{}, fmt.Errorf("client: failed to decode job submission response: %w", err)
+	}
+	handle.Endpoint = req.Endpoint
+
+	if err := j.store.Save(ctx, JobRecord{
+		ID:         handle.ID,
+		Endpoint:   handle.Endpoint,
+		CreatedAt:  handle.SubmittedAt,
+		LastStatus: JobStatusPending,
+	}); err != nil {
+		// The job was already created server-side, so the handle is
+		// still returned alongside the error: callers can still poll
+		// or wait on it even though it could not be persisted locally
+		// for resuming across a process restart
+		return handle, fmt.Errorf("client: job %q was submitted but failed to persist: %w", handle.ID, err)
+	}
+
+	return handle, nil
+}
+
+// Status fetches the current status of the job identified by id
+func (j *Jobs) Status(ctx context.Context, id string) (JobStatus, error) {
+	url := j.config.GetEndpointURL(jobsService, id)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := j.config.Authorize(ctx, httpReq); err != nil {
+		return "", err
+	}
+
+	res, err := j.config.Transport().Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Status JobStatus `json:"status"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("client: failed to decode job status response: %w", err)
+	}
+
+	if record, err := j.store.Load(ctx, id); err == nil {
+		record.LastStatus = body.Status
+		_ = j.store.Save(ctx, record)
+	}
+
+	return body.Status, nil
+}
+
+// Wait polls the job identified by id until it reaches a terminal
+// status, backing off PollInterval up to MaxInterval between checks,
+// and returns its raw result, for JobResult.Decode to unmarshal, once
+// it succeeds
+func (j *Jobs) Wait(ctx context.Context, id string, opts WaitOptions) (JobResult, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	for {
+		status, err := j.Status(ctx, id)
+		if err != nil {
+			return JobResult{}, err
+		}
+
+		if status.terminal() {
+			if status == JobStatusFailed {
+				return JobResult{}, fmt.Errorf("client: job %q failed", id)
+			}
+			return j.result(ctx, id)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return JobResult{}, ctx.Err()
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// result fetches the raw, undecoded result of a succeeded job
+func (j *Jobs) result(ctx context.Context, id string) (JobResult, error) {
+	url := j.config.GetEndpointURL(jobsService, id) + "/result"
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return JobResult{}, err
+	}
+	if err := j.config.Authorize(ctx, httpReq); err != nil {
+		return JobResult{}, err
+	}
+
+	res, err := j.config.Transport().Do(httpReq)
+	if err != nil {
+		return JobResult{}, err
+	}
+	defer res.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, res.Body); err != nil {
+		return JobResult{}, err
+	}
+
+	return JobResult{Raw: buf.Bytes()}, nil
+}
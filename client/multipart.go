@@ -0,0 +1,70 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/glair-ai/glair-vision-go"
+)
+
+// newMultipartBody encodes image as a multipart/form-data body under
+// an "image" form field, returning the encoded body and its content
+// type, ready to use as an http.Request body or a JobRequest.Payload
+func newMultipartBody(image io.Reader) (io.Reader, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("image", "image")
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, image); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &body, writer.FormDataContentType(), nil
+}
+
+// newMultipartRequest builds an authorized multipart/form-data POST
+// request for service/endpoint, uploading image under the "image"
+// form field
+func newMultipartRequest(ctx context.Context, config *glair.Config, service, endpoint string, image io.Reader) (*http.Request, error) {
+	body, contentType, err := newMultipartBody(image)
+	if err != nil {
+		return nil, err
+	}
+
+	url := config.GetEndpointURL(service, endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	if err := config.Authorize(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// decodeJSON decodes res's body as JSON into v, returning an error
+// that includes the response body when the request did not succeed
+func decodeJSON(res *http.Response, v interface{}) error {
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		raw, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("client: request failed with status %d: %s", res.StatusCode, raw)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
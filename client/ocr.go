@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+
+	"github.com/glair-ai/glair-vision-go"
+	"github.com/glair-ai/glair-vision-go/ocr"
+)
+
+// ocrService is the service path used to build OCR endpoint URLs
+const ocrService = "ocr"
+
+// OCR provides document text extraction operations
+type OCR struct {
+	config *glair.Config
+	jobs   *Jobs
+}
+
+// GeneralDocument performs synchronous OCR of an all-purpose general
+// document
+func (o *OCR) GeneralDocument(ctx context.Context, input ocr.GeneralDocumentInput) (*ocr.GeneralDocument, error) {
+	req, err := newMultipartRequest(ctx, o.config, ocrService, "general-document", input.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := o.config.Transport().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ocr.GeneralDocument
+	if err := decodeJSON(res, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GeneralDocumentAsync submits input as an asynchronous job instead of
+// waiting synchronously, for large documents where synchronous OCR may
+// take too long for a single request/response cycle. Use o.jobs.Wait
+// (or Client.Jobs.Wait) with the returned JobHandle.ID to retrieve the
+// result once it is ready
+func (o *OCR) GeneralDocumentAsync(ctx context.Context, input ocr.GeneralDocumentInput) (JobHandle, error) {
+	body, contentType, err := newMultipartBody(input.Image)
+	if err != nil {
+		return JobHandle{}, err
+	}
+
+	return o.jobs.Submit(ctx, JobRequest{
+		Endpoint:    "general-document",
+		Payload:     body,
+		ContentType: contentType,
+	})
+}
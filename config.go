@@ -54,6 +54,25 @@ type Config struct {
 	// GLAIR Vision Go SDK to log necessary informations.
 	// Defaults to no log
 	Logger Logger
+
+	// CredentialProvider, when set, is consulted for credentials on
+	// every outgoing request instead of the static Username/Password/
+	// ApiKey fields. This allows dynamic credential sources such as
+	// env/keychain loaders or an OAuth2Provider. Defaults to nil,
+	// which preserves today's static credential behavior
+	CredentialProvider CredentialProvider
+
+	// Retry configures automatic retry of transient failures for
+	// requests sent through Transport(). Defaults to the zero value,
+	// which disables retry and preserves today's behavior
+	Retry RetryPolicy
+}
+
+// Transport returns the HTTPClient that should be used to send
+// requests: the configured Client, wrapped with retry behavior when
+// Retry is enabled
+func (c *Config) Transport() HTTPClient {
+	return newRetryingClient(c.Client, c.Retry, c.Logger)
 }
 
 // NewConfig creates a new configuration object with default values for
@@ -0,0 +1,192 @@
+package glair
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultContextName is the context used when none is selected through
+// GLAIR_CONTEXT or Config.WithContext
+const defaultContextName = "default"
+
+// contextEnvVar is the environment variable used to select the active
+// context when one is not explicitly requested
+const contextEnvVar = "GLAIR_CONTEXT"
+
+// Context represents a single named entry in the glair config file,
+// analogous to a Docker context: its own credentials, base URL, and
+// API version, so a user can switch environments without recompiling
+type Context struct {
+	Name       string `yaml:"-"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	ApiKey     string `yaml:"api_key"`
+	BaseUrl    string `yaml:"base_url"`
+	ApiVersion string `yaml:"api_version"`
+}
+
+// contextFile mirrors the on-disk layout of the glair config file
+type contextFile struct {
+	Contexts map[string]Context `yaml:"contexts"`
+}
+
+// configFilePath returns the path to the glair config file,
+// defaulting to "~/.glair/config.yaml"
+func configFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".glair", "config.yaml"), nil
+}
+
+// readContextFile loads and parses the glair config file. A missing
+// file is not an error: it is treated as if it defined no contexts, so
+// callers fall back to env vars and defaults
+func readContextFile() (*contextFile, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &contextFile{Contexts: map[string]Context{}}, nil
+		}
+		return nil, fmt.Errorf("glair: failed to read config file: %w", err)
+	}
+
+	var file contextFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("glair: failed to parse config file: %w", err)
+	}
+
+	if file.Contexts == nil {
+		file.Contexts = map[string]Context{}
+	}
+
+	return &file, nil
+}
+
+// ListContexts returns the names of every context defined in the glair
+// config file
+func ListContexts() ([]string, error) {
+	file, err := readContextFile()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(file.Contexts))
+	for name := range file.Contexts {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// LoadContext builds a *Config from the named entry in the glair config
+// file. An empty name resolves GLAIR_CONTEXT, falling back to
+// "default". When the config file or the named context does not exist,
+// LoadContext falls back to NewConfig populated from the GLAIR_USERNAME,
+// GLAIR_PASSWORD, and GLAIR_API_KEY environment variables.
+func LoadContext(name string) (*Config, error) {
+	if name == "" {
+		name = activeContextName()
+	}
+
+	file, err := readContextFile()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, ok := file.Contexts[name]
+	if !ok {
+		return NewConfig(
+			os.Getenv("GLAIR_USERNAME"),
+			os.Getenv("GLAIR_PASSWORD"),
+			os.Getenv("GLAIR_API_KEY"),
+		), nil
+	}
+
+	return configFromContext(ctx), nil
+}
+
+// configFromContext builds a *Config from a parsed context entry
+func configFromContext(ctx Context) *Config {
+	config := NewConfig(ctx.Username, ctx.Password, ctx.ApiKey)
+	if ctx.BaseUrl != "" {
+		config.WithBaseURL(ctx.BaseUrl)
+	}
+	if ctx.ApiVersion != "" {
+		config.WithVersion(ctx.ApiVersion)
+	}
+
+	return config
+}
+
+// activeContextName resolves the context selected through GLAIR_CONTEXT,
+// falling back to defaultContextName
+func activeContextName() string {
+	if name := os.Getenv(contextEnvVar); name != "" {
+		return name
+	}
+
+	return defaultContextName
+}
+
+// WithContext replaces the configuration object's credentials, base
+// URL, and API version with those of the named context from the glair
+// config file, leaving c unchanged when the config file cannot be read
+// or does not define that context. Unlike LoadContext, WithContext
+// never falls back to env vars: that fallback only makes sense when
+// building a brand new Config, not when a caller who already has one
+// asks to switch it to a context that turns out not to exist
+func (c *Config) WithContext(name string) *Config {
+	if c == nil {
+		return nil
+	}
+
+	if name == "" {
+		name = activeContextName()
+	}
+
+	file, err := readContextFile()
+	if err != nil {
+		return c
+	}
+
+	ctx, ok := file.Contexts[name]
+	if !ok {
+		return c
+	}
+
+	loaded := configFromContext(ctx)
+	c.Username = loaded.Username
+	c.Password = loaded.Password
+	c.ApiKey = loaded.ApiKey
+	c.BaseUrl = loaded.BaseUrl
+	c.ApiVersion = loaded.ApiVersion
+	return c
+}
+
+// UseContext persists name as the active context by writing it to the
+// GLAIR_CONTEXT environment variable for the current process. Shells
+// or subsequent processes that want the selection to persist should
+// export GLAIR_CONTEXT themselves
+func UseContext(name string) error {
+	file, err := readContextFile()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Contexts[name]; !ok {
+		return fmt.Errorf("glair: context %q is not defined", name)
+	}
+
+	return os.Setenv(contextEnvVar, name)
+}
@@ -0,0 +1,137 @@
+package glair
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// credentialHelperBinaryPrefix mirrors the docker-credential-* naming
+// convention: a helper named "osxkeychain" resolves to the binary
+// "glair-credential-osxkeychain"
+const credentialHelperBinaryPrefix = "glair-credential-"
+
+// CredentialHelper resolves credentials by delegating to an external
+// helper binary, following the same pattern as Docker's
+// docker-credential-* helpers (osxkeychain, secretservice, wincred,
+// pass, ...), so teams can reuse the secret store they already use for
+// container registries instead of keeping credentials in source or
+// plaintext env vars
+type CredentialHelper interface {
+	// Get resolves credentials for serverURL
+	Get(ctx context.Context, serverURL string) (Credentials, error)
+}
+
+// execCredentialHelper is a CredentialHelper that shells out to a
+// docker-credential-* style binary, writing serverURL to its stdin and
+// parsing a JSON {Username, Secret} reply from its stdout
+type execCredentialHelper struct {
+	binary string
+}
+
+// NewCredentialHelper returns a CredentialHelper that invokes the
+// "glair-credential-<name> get" binary to resolve credentials, e.g.
+// NewCredentialHelper("osxkeychain") invokes "glair-credential-osxkeychain"
+func NewCredentialHelper(name string) CredentialHelper {
+	return &execCredentialHelper{binary: credentialHelperBinaryPrefix + name}
+}
+
+func (h *execCredentialHelper) Get(ctx context.Context, serverURL string) (Credentials, error) {
+	cmd := exec.CommandContext(ctx, h.binary, "get")
+	cmd.Stdin = bytes.NewBufferString(serverURL)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return Credentials{}, fmt.Errorf("glair: credential helper %q failed: %w", h.binary, err)
+	}
+
+	var reply struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &reply); err != nil {
+		return Credentials{}, fmt.Errorf("glair: failed to parse credential helper %q reply: %w", h.binary, err)
+	}
+
+	return Credentials{
+		Username: reply.Username,
+		Password: reply.Secret,
+	}, nil
+}
+
+// credentialHelperProvider is a CredentialProvider that resolves
+// credentials through a CredentialHelper, caching the result in-memory
+// for TTL so that every request does not shell out to the helper
+// binary
+type credentialHelperProvider struct {
+	helper    CredentialHelper
+	serverURL string
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	cached    Credentials
+	cachedAt  time.Time
+	hasCached bool
+}
+
+func (p *credentialHelperProvider) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hasCached && time.Since(p.cachedAt) < p.ttl {
+		return p.cached, nil
+	}
+
+	credentials, err := p.helper.Get(ctx, p.serverURL)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	p.cached = credentials
+	p.cachedAt = time.Now()
+	p.hasCached = true
+	return credentials, nil
+}
+
+// defaultCredentialHelperTTL bounds how long a credential helper's
+// reply is cached before it is invoked again
+const defaultCredentialHelperTTL = 5 * time.Minute
+
+// WithCredentialHelper sets a CredentialProvider for the configuration
+// object that resolves credentials at request time by invoking the
+// "glair-credential-<name>" binary against c.BaseUrl, caching the
+// result in-memory for defaultCredentialHelperTTL. Use
+// WithCredentialHelperTTL to override the cache duration
+func (c *Config) WithCredentialHelper(name string) *Config {
+	if c == nil {
+		return nil
+	}
+
+	c.CredentialProvider = &credentialHelperProvider{
+		helper:    NewCredentialHelper(name),
+		serverURL: c.BaseUrl,
+		ttl:       defaultCredentialHelperTTL,
+	}
+	return c
+}
+
+// WithCredentialHelperTTL overrides how long the credential helper
+// installed by WithCredentialHelper caches its reply in-memory before
+// invoking the helper binary again. It is a no-op unless
+// WithCredentialHelper has already been called on c
+func (c *Config) WithCredentialHelperTTL(ttl time.Duration) *Config {
+	if c == nil {
+		return nil
+	}
+
+	if provider, ok := c.CredentialProvider.(*credentialHelperProvider); ok && ttl > 0 {
+		provider.ttl = ttl
+	}
+	return c
+}
@@ -0,0 +1,317 @@
+package glair
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials represents a resolved set of credentials that a
+// CredentialProvider hands back to the client for a single request.
+//
+// The basic-auth fields (Username/Password) and ApiKey may be populated
+// together, as the static credentials on Config are. When AccessToken is
+// populated, it is sent on its own and the other fields are ignored.
+type Credentials struct {
+	// Username represents username to be used for basic authentication
+	// with GLAIR Vision API
+	Username string
+	// Password represents password to be used for basic authentication
+	// with GLAIR Vision API
+	Password string
+	// ApiKey represents API key to be used for authentication
+	// with GLAIR Vision API
+	ApiKey string
+	// AccessToken represents a bearer token to be sent in the
+	// Authorization header, e.g. one obtained through OAuth2
+	AccessToken string
+}
+
+// CredentialProvider is an interface that users can implement to
+// customize how credentials are resolved for each outgoing request.
+// This allows static credentials (the current default), credentials
+// loaded from the environment or an OS keychain, and dynamically
+// refreshed credentials such as OAuth2 access tokens to share the
+// same integration point.
+type CredentialProvider interface {
+	// Credentials resolves the credentials to use for the next
+	// outgoing request. Implementations are called once per request
+	// and should be safe for concurrent use.
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// staticCredentialProvider is the default CredentialProvider, backed
+// by the static Username/Password/ApiKey fields on Config. It preserves
+// today's behavior for users who do not configure a custom provider.
+type staticCredentialProvider struct {
+	config *Config
+}
+
+func (p *staticCredentialProvider) Credentials(ctx context.Context) (Credentials, error) {
+	return Credentials{
+		Username: p.config.Username,
+		Password: p.config.Password,
+		ApiKey:   p.config.ApiKey,
+	}, nil
+}
+
+// WithCredentialProvider sets a custom CredentialProvider for the
+// configuration object. When set, it takes precedence over the static
+// Username/Password/ApiKey fields when resolving credentials for a request.
+func (c *Config) WithCredentialProvider(provider CredentialProvider) *Config {
+	if c == nil {
+		return nil
+	}
+
+	c.CredentialProvider = provider
+	return c
+}
+
+// resolveCredentials returns the credentials resolved through the
+// CredentialProvider configured for c, falling back to the static Username/Password/ApiKey fields when none
+// is set.
+func (c *Config) resolveCredentials(ctx context.Context) (Credentials, error) {
+	provider := c.CredentialProvider
+	if provider == nil {
+		provider = &staticCredentialProvider{config: c}
+	}
+
+	return provider.Credentials(ctx)
+}
+
+// Authorize resolves credentials from c and applies them to req, using
+// a Bearer Authorization header for OAuth2-style access tokens, and
+// otherwise an x-api-key header for ApiKey and HTTP basic
+// authentication for Username/Password.
+func (c *Config) Authorize(ctx context.Context, req *http.Request) error {
+	credentials, err := c.resolveCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	if credentials.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+credentials.AccessToken)
+		return nil
+	}
+
+	if credentials.ApiKey != "" {
+		req.Header.Set("x-api-key", credentials.ApiKey)
+	}
+
+	if credentials.Username != "" || credentials.Password != "" {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	return nil
+}
+
+// oauth2RefreshJitter bounds how much random jitter is added to a
+// background token refresh so that many client instances sharing the
+// same refresh token do not all refresh at the exact same moment.
+const oauth2RefreshJitter = 10 * time.Second
+
+// OAuth2Provider is a built-in CredentialProvider that exchanges a
+// long-lived refresh token for short-lived access tokens against an
+// OAuth2 token endpoint. Once the access token is within RefreshBefore
+// of expiring, it is refreshed in a background goroutine so that
+// concurrent callers keep using the current token, even one that has
+// already expired, instead of blocking on the token endpoint round
+// trip; a mutex-guarded flag ensures at most one background refresh is
+// in flight at a time. A Credentials call made before any token has
+// been fetched yet has nothing to serve and blocks synchronously on the
+// exchange; concurrent calls of that kind are not deduplicated, and
+// each performs its own exchange.
+type OAuth2Provider struct {
+	// TokenURL is the OAuth2 token endpoint used to exchange the
+	// refresh token for an access token
+	TokenURL string
+	// ClientID is the OAuth2 client identifier
+	ClientID string
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string
+	// RefreshToken is the long-lived token exchanged for access tokens
+	RefreshToken string
+
+	// Client is the HTTP client used to call TokenURL. Defaults to
+	// http.DefaultClient
+	Client HTTPClient
+
+	// RefreshBefore controls how long before expiry the access token
+	// is proactively refreshed. Defaults to 30 seconds
+	RefreshBefore time.Duration
+	// RefreshTimeout bounds how long a background refresh is allowed
+	// to run, since it is detached from any caller's context. Defaults
+	// to 30 seconds
+	RefreshTimeout time.Duration
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+	refreshing  bool
+}
+
+// NewOAuth2Provider creates an OAuth2Provider that refreshes access
+// tokens against tokenURL using the given client credentials and
+// refresh token.
+func NewOAuth2Provider(tokenURL, clientID, clientSecret, refreshToken string) *OAuth2Provider {
+	return &OAuth2Provider{
+		TokenURL:       tokenURL,
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		RefreshToken:   refreshToken,
+		Client:         defaultClient,
+		RefreshBefore:  30 * time.Second,
+		RefreshTimeout: 30 * time.Second,
+	}
+}
+
+// Credentials returns the current access token, kicking off a
+// background refresh once it is within RefreshBefore of expiring.
+// Callers in flight while that refresh runs keep getting the current
+// token, whether or not it has already expired; only a call made when
+// no token has been fetched yet blocks on a synchronous exchange.
+func (p *OAuth2Provider) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	token := p.accessToken
+	nearExpiry := token == "" || time.Now().After(p.expiresAt.Add(-p.refreshBefore()))
+	p.mu.Unlock()
+
+	if token == "" {
+		if err := p.refreshAndStore(ctx); err != nil {
+			return Credentials{}, err
+		}
+
+		p.mu.Lock()
+		token = p.accessToken
+		p.mu.Unlock()
+		return Credentials{AccessToken: token}, nil
+	}
+
+	if nearExpiry {
+		p.startBackgroundRefresh()
+	}
+
+	return Credentials{AccessToken: token}, nil
+}
+
+func (p *OAuth2Provider) refreshBefore() time.Duration {
+	if p.RefreshBefore > 0 {
+		return p.RefreshBefore
+	}
+
+	return 30 * time.Second
+}
+
+func (p *OAuth2Provider) refreshTimeout() time.Duration {
+	if p.RefreshTimeout > 0 {
+		return p.RefreshTimeout
+	}
+
+	return 30 * time.Second
+}
+
+// startBackgroundRefresh refreshes the access token in its own
+// goroutine, detached from any caller's context, unless a refresh is
+// already in flight
+func (p *OAuth2Provider) startBackgroundRefresh() {
+	p.mu.Lock()
+	if p.refreshing {
+		p.mu.Unlock()
+		return
+	}
+	p.refreshing = true
+	p.mu.Unlock()
+
+	go func() {
+		defer func() {
+			p.mu.Lock()
+			p.refreshing = false
+			p.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.refreshTimeout())
+		defer cancel()
+
+		// a failed background refresh simply leaves the previous token
+		// in place; the next Credentials call will see it is still
+		// near expiry and trigger another attempt
+		_ = p.refreshAndStore(ctx)
+	}()
+}
+
+// refreshAndStore exchanges the refresh token for a new access token
+// and stores the result, subtracting up to oauth2RefreshJitter of
+// random jitter from the computed expiry so that
+// many provider instances sharing the same refresh token do not all
+// refresh at the exact same moment
+func (p *OAuth2Provider) refreshAndStore(ctx context.Context) error {
+	token, expiresIn, err := p.exchangeRefreshToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(oauth2RefreshJitter)))
+
+	p.mu.Lock()
+	p.accessToken = token
+	p.expiresAt = time.Now().Add(expiresIn - jitter)
+	p.mu.Unlock()
+
+	return nil
+}
+
+// exchangeRefreshToken calls the OAuth2 token endpoint and returns the
+// new access token and its lifetime. It holds no lock and mutates no
+// provider state, so it is safe to call from either the synchronous
+// first-fetch path or the background refresh goroutine
+func (p *OAuth2Provider) exchangeRefreshToken(ctx context.Context) (string, time.Duration, error) {
+	client := p.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {p.RefreshToken},
+		"client_id":     {p.ClientID},
+		"client_secret": {p.ClientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("glair: failed to refresh oauth2 token: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		raw, _ := io.ReadAll(res.Body)
+		return "", 0, fmt.Errorf("glair: oauth2 token endpoint returned status %d: %s", res.StatusCode, raw)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("glair: failed to decode oauth2 token response: %w", err)
+	}
+
+	if body.AccessToken == "" {
+		return "", 0, fmt.Errorf("glair: oauth2 token endpoint returned an empty access token")
+	}
+
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,130 @@
+package glair
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenEndpoint is a minimal OAuth2 token endpoint that counts
+// requests and can be told to fail the next N of them
+type tokenEndpoint struct {
+	server    *httptest.Server
+	calls     int32
+	failNext  int32
+	expiresIn int64
+}
+
+func newTokenEndpoint(t *testing.T, expiresIn int64) *tokenEndpoint {
+	e := &tokenEndpoint{expiresIn: expiresIn}
+	e.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&e.calls, 1)
+
+		if atomic.LoadInt32(&e.failNext) > 0 {
+			atomic.AddInt32(&e.failNext, -1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", atomic.LoadInt32(&e.calls)),
+			"expires_in":   e.expiresIn,
+		})
+	}))
+	t.Cleanup(e.server.Close)
+	return e
+}
+
+func TestOAuth2ProviderFirstFetchIsSynchronous(t *testing.T) {
+	endpoint := newTokenEndpoint(t, 3600)
+	provider := NewOAuth2Provider(endpoint.server.URL, "id", "secret", "refresh-token")
+
+	creds, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.AccessToken != "token-1" {
+		t.Fatalf("expected token-1, got %q", creds.AccessToken)
+	}
+	if atomic.LoadInt32(&endpoint.calls) != 1 {
+		t.Fatalf("expected exactly 1 token endpoint call, got %d", endpoint.calls)
+	}
+}
+
+func TestOAuth2ProviderFirstFetchPropagatesError(t *testing.T) {
+	endpoint := newTokenEndpoint(t, 3600)
+	atomic.StoreInt32(&endpoint.failNext, 1)
+	provider := NewOAuth2Provider(endpoint.server.URL, "id", "secret", "refresh-token")
+
+	_, err := provider.Credentials(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error when the token endpoint rejects the refresh")
+	}
+}
+
+func TestOAuth2ProviderServesStaleTokenWhileRefreshingInBackground(t *testing.T) {
+	endpoint := newTokenEndpoint(t, 0)
+	provider := NewOAuth2Provider(endpoint.server.URL, "id", "secret", "refresh-token")
+	provider.RefreshBefore = time.Hour // always "near expiry" after the first fetch
+
+	first, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error on first fetch: %v", err)
+	}
+
+	// the token is already within RefreshBefore of expiring, so this
+	// call must trigger a background refresh while still returning the
+	// still-cached token synchronously, without blocking on it
+	second, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error on second fetch: %v", err)
+	}
+	if second.AccessToken != first.AccessToken {
+		t.Fatalf("expected the stale token %q to be served while refreshing, got %q", first.AccessToken, second.AccessToken)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if atomic.LoadInt32(&endpoint.calls) >= 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if calls := atomic.LoadInt32(&endpoint.calls); calls < 2 {
+		t.Fatalf("expected the background refresh to have called the token endpoint again, got %d calls", calls)
+	}
+}
+
+func TestOAuth2ProviderDedupesConcurrentBackgroundRefreshes(t *testing.T) {
+	endpoint := newTokenEndpoint(t, 0)
+	provider := NewOAuth2Provider(endpoint.server.URL, "id", "secret", "refresh-token")
+	provider.RefreshBefore = time.Hour
+
+	if _, err := provider.Credentials(context.Background()); err != nil {
+		t.Fatalf("unexpected error on first fetch: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			provider.Credentials(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	time.Sleep(50 * time.Millisecond)
+
+	// one synchronous first fetch, plus at most one deduped background
+	// refresh triggered by the 20 concurrent near-expiry calls
+	if calls := atomic.LoadInt32(&endpoint.calls); calls > 2 {
+		t.Fatalf("expected concurrent near-expiry calls to dedupe to a single background refresh, got %d token endpoint calls", calls)
+	}
+}
@@ -0,0 +1,16 @@
+package glair
+
+import "io"
+
+// PassiveLivenessInput is the input for FaceBio.PassiveLiveness: an
+// image to check for signs of a live subject
+type PassiveLivenessInput struct {
+	// Image is the face image to analyze
+	Image io.Reader
+}
+
+// PassiveLivenessResult stores the result of a passive liveness check
+type PassiveLivenessResult struct {
+	IsLive     bool    `json:"is_live"`
+	Confidence float64 `json:"confidence"`
+}
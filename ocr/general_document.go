@@ -1,5 +1,7 @@
 package ocr
 
+import "io"
+
 // GeneralDocument stores OCR result of all-purpose general model\
 // from the given input
 type GeneralDocument = OCRResult[GeneralDocumentData]
@@ -7,3 +9,10 @@ type GeneralDocument = OCRResult[GeneralDocumentData]
 type GeneralDocumentData struct {
 	AllTexts []OCRField `json:"all_texts,omitempty"`
 }
+
+// GeneralDocumentInput is the input for the all-purpose general
+// document OCR model
+type GeneralDocumentInput struct {
+	// Image is the document image to extract text from
+	Image io.Reader
+}
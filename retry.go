@@ -0,0 +1,223 @@
+package glair
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryableFunc decides whether a request should be retried given the
+// response (which may be nil on a network error) and the error
+// returned by the underlying HTTPClient
+type RetryableFunc func(res *http.Response, err error) bool
+
+// RetryPolicy configures automatic retry of transient failures -
+// network errors, HTTP 429, and 5xx responses - with exponential
+// backoff and full jitter. The zero value disables retry, preserving
+// today's behavior
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the
+	// first one. A value <= 1 disables retry
+	MaxAttempts int
+	// InitialInterval is the backoff interval before the first retry
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff interval between retries
+	MaxInterval time.Duration
+	// Multiplier is applied to the backoff interval after every
+	// attempt. Defaults to 2 when <= 0
+	Multiplier float64
+	// Retryable decides whether a given response/error pair should be
+	// retried. Defaults to DefaultRetryable when nil
+	Retryable RetryableFunc
+}
+
+// enabled reports whether the policy allows more than one attempt
+func (p RetryPolicy) enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+func (p RetryPolicy) multiplier() float64 {
+	if p.Multiplier > 0 {
+		return p.Multiplier
+	}
+
+	return 2
+}
+
+func (p RetryPolicy) retryable() RetryableFunc {
+	if p.Retryable != nil {
+		return p.Retryable
+	}
+
+	return DefaultRetryable
+}
+
+// DefaultRetryable retries network errors, HTTP 429, and any 5xx
+// response
+func DefaultRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	if res == nil {
+		return false
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// WithRetry sets the retry policy for the configuration object. Retry
+// is off by default: requests are sent exactly once unless a policy
+// with MaxAttempts > 1 is configured
+func (c *Config) WithRetry(policy RetryPolicy) *Config {
+	if c == nil {
+		return nil
+	}
+
+	c.Retry = policy
+	return c
+}
+
+// retryingClient wraps the user-configured HTTPClient, reattempting
+// requests that the wrapped RetryPolicy considers retryable
+type retryingClient struct {
+	client HTTPClient
+	policy RetryPolicy
+	logger Logger
+}
+
+// newRetryingClient wraps client with policy when retry is enabled,
+// otherwise it returns client unchanged
+func newRetryingClient(client HTTPClient, policy RetryPolicy, logger Logger) HTTPClient {
+	if !policy.enabled() {
+		return client
+	}
+
+	return &retryingClient{client: client, policy: policy, logger: logger}
+}
+
+func (r *retryingClient) Do(req *http.Request) (*http.Response, error) {
+	body, hasBody, err := snapshotRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *http.Response
+
+	for attempt := 1; attempt <= r.policy.MaxAttempts; attempt++ {
+		if attempt > 1 && hasBody {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		next, nextErr := r.client.Do(req)
+
+		// the previous attempt's response, if any, is superseded the
+		// moment we have a new one - whether next is now returned to
+		// the caller or itself gets retried - so close it here rather
+		// than only on the retry path, or the next attempt's own
+		// response leaks when this is the one that finally succeeds
+		drainAndClose(res)
+		res, err = next, nextErr
+
+		if !r.policy.retryable()(res, err) {
+			return res, err
+		}
+
+		if attempt == r.policy.MaxAttempts {
+			break
+		}
+
+		wait := retryDelay(r.policy, attempt, res)
+		r.logf("glair: retrying request to %s in %s (attempt %d/%d)", req.URL, wait, attempt+1, r.policy.MaxAttempts)
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			// the caller only sees ctx.Err(), so res must be closed
+			// here rather than handed back - mirrors the net/http
+			// client contract that res is nil whenever err is non-nil
+			drainAndClose(res)
+			return nil, req.Context().Err()
+		}
+	}
+
+	return res, err
+}
+
+// drainAndClose drains and closes res.Body so its connection can be
+// reused/released, tolerating a nil res or body
+func drainAndClose(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+}
+
+func (r *retryingClient) logf(format string, args ...interface{}) {
+	if r.logger == nil {
+		return
+	}
+
+	r.logger.Debugf(format, args...)
+}
+
+// snapshotRequestBody drains req.Body, if any, into memory and
+// replaces it with a fresh reader over the drained bytes, returning
+// those bytes so the retry loop can rebuild a readable body ahead of
+// every attempt after the first. A request with no body at all (e.g. a
+// GET) reports hasBody=false and is always replayable as-is
+func snapshotRequestBody(req *http.Request) (body []byte, hasBody bool, err error) {
+	if req.Body == nil {
+		return nil, false, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, false, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	return data, true, nil
+}
+
+// noMaxInterval is the ceiling applied when RetryPolicy.MaxInterval is
+// left at its zero value. MaxInterval is documented as optional, so
+// backoff growth must still be bounded - and bounded well short of
+// time.Duration's range, so that int64(max)+1 below can never overflow
+// and panic inside rand.Int63n
+const noMaxInterval = 100 * 365 * 24 * time.Hour
+
+// retryDelay computes the backoff interval before the given attempt,
+// honoring a Retry-After header (in seconds) on res when present and
+// otherwise applying exponential backoff with full jitter, bounded by
+// RetryPolicy.MaxInterval (or noMaxInterval when unset). A Retry-After
+// delay is returned as-is, even when it exceeds MaxInterval
+func retryDelay(policy RetryPolicy, attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	ceiling := policy.MaxInterval
+	if ceiling <= 0 {
+		ceiling = noMaxInterval
+	}
+
+	max := float64(policy.InitialInterval) * math.Pow(policy.multiplier(), float64(attempt-1))
+	if max > float64(ceiling) {
+		max = float64(ceiling)
+	}
+
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
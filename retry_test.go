@@ -0,0 +1,197 @@
+package glair
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryDelayBoundedByMaxInterval(t *testing.T) {
+	policy := RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+
+	for attempt := 1; attempt <= 20; attempt++ {
+		delay := retryDelay(policy, attempt, nil)
+		if delay > policy.MaxInterval {
+			t.Fatalf("attempt %d: delay %s exceeds MaxInterval %s", attempt, delay, policy.MaxInterval)
+		}
+		if delay < 0 {
+			t.Fatalf("attempt %d: delay %s is negative", attempt, delay)
+		}
+	}
+}
+
+func TestRetryDelayUnboundedMaxIntervalNeverPanics(t *testing.T) {
+	policy := RetryPolicy{
+		InitialInterval: time.Millisecond,
+		Multiplier:      2,
+	}
+
+	for attempt := 1; attempt <= 64; attempt++ {
+		delay := retryDelay(policy, attempt, nil)
+		if delay > noMaxInterval {
+			t.Fatalf("attempt %d: delay %s exceeds noMaxInterval %s", attempt, delay, noMaxInterval)
+		}
+		if delay < 0 {
+			t.Fatalf("attempt %d: delay %s is negative", attempt, delay)
+		}
+	}
+}
+
+func TestRetryDelayHonorsRetryAfterHeader(t *testing.T) {
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	policy := RetryPolicy{InitialInterval: time.Millisecond, MaxInterval: time.Second}
+
+	delay := retryDelay(policy, 1, res)
+	if delay != 7*time.Second {
+		t.Fatalf("expected 7s delay from Retry-After, got %s", delay)
+	}
+}
+
+// trackingBody wraps a bytes.Reader so tests can assert it was closed
+type trackingBody struct {
+	*bytes.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+// sequenceClient returns a canned sequence of responses, one per call,
+// and counts the calls made
+type sequenceClient struct {
+	responses []*http.Response
+	bodies    []*trackingBody
+	calls     int
+}
+
+func (c *sequenceClient) Do(req *http.Request) (*http.Response, error) {
+	res := c.responses[c.calls]
+	c.calls++
+	return res, nil
+}
+
+func newTrackingResponse(status int) (*http.Response, *trackingBody) {
+	body := &trackingBody{Reader: bytes.NewReader(nil)}
+	return &http.Response{StatusCode: status, Header: make(http.Header), Body: body}, body
+}
+
+func TestRetryingClientClosesIntermediateResponses(t *testing.T) {
+	res1, body1 := newTrackingResponse(http.StatusInternalServerError)
+	res2, body2 := newTrackingResponse(http.StatusInternalServerError)
+	res3, body3 := newTrackingResponse(http.StatusOK)
+
+	client := &sequenceClient{responses: []*http.Response{res1, res2, res3}}
+	transport := newRetryingClient(client, RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	res, err := transport.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res != res3 {
+		t.Fatalf("expected the final attempt's response to be returned")
+	}
+
+	if !body1.closed {
+		t.Errorf("first (discarded) response body was never closed")
+	}
+	if !body2.closed {
+		t.Errorf("second (discarded) response body was never closed")
+	}
+	if body3.closed {
+		t.Errorf("final response body should be left open for the caller to close")
+	}
+}
+
+func TestRetryingClientReplaysBodyAcrossAttempts(t *testing.T) {
+	res1, _ := newTrackingResponse(http.StatusInternalServerError)
+	res2, _ := newTrackingResponse(http.StatusOK)
+
+	client := &sequenceClient{responses: []*http.Response{res1, res2}}
+	transport := newRetryingClient(client, RetryPolicy{
+		MaxAttempts:     2,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", bytes.NewReader([]byte("payload")))
+	if _, err := transport.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", client.calls)
+	}
+
+	replayed, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("failed to read replayed body: %v", err)
+	}
+	if string(replayed) != "payload" {
+		t.Fatalf("expected body to be replayed as %q, got %q", "payload", replayed)
+	}
+}
+
+type erroringClient struct {
+	err   error
+	calls int
+}
+
+func (c *erroringClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func TestRetryingClientRetriesNetworkErrors(t *testing.T) {
+	client := &erroringClient{err: errors.New("connection reset")}
+	transport := newRetryingClient(client, RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	_, err := transport.Do(req)
+	if err == nil {
+		t.Fatalf("expected the final network error to be returned")
+	}
+	if client.calls != 3 {
+		t.Fatalf("expected the network error to be retried up to 3 attempts, got %d", client.calls)
+	}
+}
+
+func TestRetryingClientStopsOnContextCancellation(t *testing.T) {
+	res1, _ := newTrackingResponse(http.StatusInternalServerError)
+	client := &sequenceClient{responses: []*http.Response{res1, res1}}
+	transport := newRetryingClient(client, RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: time.Hour,
+		MaxInterval:     time.Hour,
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	res, err := transport.Do(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if res != nil {
+		t.Fatalf("expected a nil response alongside a context error, got %v", res)
+	}
+}